@@ -0,0 +1,65 @@
+// Package adf builds Atlassian Document Format documents, the
+// structured JSON Jira Cloud's REST API v3 requires for rich-text
+// fields like an issue's description.
+package adf
+
+// Document is the top-level ADF value a Jira Cloud `description` field
+// expects.
+type Document struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	Content []Node `json:"content"`
+}
+
+// Node is a single ADF node: a block (paragraph, bulletList, listItem)
+// or an inline node (text). Fields are omitted when unused so the JSON
+// matches the shape Jira expects for each node type.
+type Node struct {
+	Type    string                 `json:"type"`
+	Content []Node                 `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []Mark                 `json:"marks,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Mark decorates a text node, e.g. a link.
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Doc wraps top-level block nodes into a complete ADF document.
+func Doc(content ...Node) Document {
+	return Document{Type: "doc", Version: 1, Content: content}
+}
+
+// Paragraph wraps inline nodes (Text, Link) into a paragraph block.
+func Paragraph(content ...Node) Node {
+	return Node{Type: "paragraph", Content: content}
+}
+
+// Text is a plain inline text node.
+func Text(s string) Node {
+	return Node{Type: "text", Text: s}
+}
+
+// Link is an inline text node that renders as a hyperlink.
+func Link(text, href string) Node {
+	return Node{
+		Type: "text",
+		Text: text,
+		Marks: []Mark{
+			{Type: "link", Attrs: map[string]interface{}{"href": href}},
+		},
+	}
+}
+
+// ListItem wraps block nodes into a single bullet point.
+func ListItem(content ...Node) Node {
+	return Node{Type: "listItem", Content: content}
+}
+
+// BulletList wraps ListItem nodes into an unordered list block.
+func BulletList(items ...Node) Node {
+	return Node{Type: "bulletList", Content: items}
+}
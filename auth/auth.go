@@ -0,0 +1,284 @@
+// Package auth provides credential storage and lookup for the external
+// services this tool talks to (Jira, GitLab, GitHub, Gitea). It is modeled
+// on git-bug's bridge/core/auth package: a small Credential interface with
+// a handful of concrete kinds, keyed by a Target and loaded from either
+// environment variables or an on-disk keyring file.
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Target identifies which service a Credential authenticates against.
+type Target string
+
+const (
+	TargetJira   Target = "jira"
+	TargetGitLab Target = "gitlab"
+	TargetGitHub Target = "github"
+	TargetGitea  Target = "gitea"
+)
+
+// Kind identifies the concrete type of a Credential, used when
+// (de)serializing credentials to the on-disk keyring file.
+type Kind string
+
+const (
+	KindToken         Kind = "token"
+	KindLoginPassword Kind = "login-password"
+	KindOAuth1        Kind = "oauth1"
+	KindOAuth2        Kind = "oauth2"
+)
+
+// Credential is something that can authenticate a request against a
+// Target service. Concrete implementations know how to apply themselves
+// to an outgoing *http.Request.
+type Credential interface {
+	// Target is the service this credential authenticates against.
+	Target() Target
+	// Kind is the concrete credential type, used for keyring storage.
+	Kind() Kind
+	// SetAuth applies the credential to an outgoing request, e.g. by
+	// setting an Authorization or PRIVATE-TOKEN header.
+	SetAuth(req *http.Request)
+}
+
+// TokenCredential is a single bearer/private token, e.g. a GitLab
+// PRIVATE-TOKEN or a GitHub personal access token.
+type TokenCredential struct {
+	target Target
+	Token  string
+}
+
+func NewTokenCredential(target Target, token string) *TokenCredential {
+	return &TokenCredential{target: target, Token: token}
+}
+
+func (c *TokenCredential) Target() Target { return c.target }
+func (c *TokenCredential) Kind() Kind     { return KindToken }
+
+// SetAuth sets the header convention appropriate for the credential's
+// target: GitLab uses PRIVATE-TOKEN, everything else a bearer token.
+func (c *TokenCredential) SetAuth(req *http.Request) {
+	if c.target == TargetGitLab {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+}
+
+// LoginPasswordCredential is a basic-auth username/password pair, e.g.
+// a Jira user email + API token used over HTTP basic auth.
+type LoginPasswordCredential struct {
+	target   Target
+	Login    string
+	Password string
+}
+
+func NewLoginPasswordCredential(target Target, login, password string) *LoginPasswordCredential {
+	return &LoginPasswordCredential{target: target, Login: login, Password: password}
+}
+
+func (c *LoginPasswordCredential) Target() Target { return c.target }
+func (c *LoginPasswordCredential) Kind() Kind     { return KindLoginPassword }
+
+func (c *LoginPasswordCredential) SetAuth(req *http.Request) {
+	req.SetBasicAuth(c.Login, c.Password)
+}
+
+// OAuth1Credential is an OAuth 1.0a token/secret pair, used for Jira
+// Server/Data Center which does not support OAuth2.
+type OAuth1Credential struct {
+	target      Target
+	ConsumerKey string
+	PrivateKey  string
+	Token       string
+	TokenSecret string
+}
+
+func NewOAuth1Credential(target Target, consumerKey, privateKey, token, tokenSecret string) *OAuth1Credential {
+	return &OAuth1Credential{
+		target:      target,
+		ConsumerKey: consumerKey,
+		PrivateKey:  privateKey,
+		Token:       token,
+		TokenSecret: tokenSecret,
+	}
+}
+
+func (c *OAuth1Credential) Target() Target { return c.target }
+func (c *OAuth1Credential) Kind() Kind     { return KindOAuth1 }
+
+// SetAuth sets a bare OAuth1 Authorization header carrying the access
+// token. Full request signing (oauth_signature over consumer/private
+// key) is handled by the jira package's signing transport, since it
+// needs the method and URL this interface doesn't see.
+func (c *OAuth1Credential) SetAuth(req *http.Request) {
+	req.Header.Set("Authorization", `OAuth oauth_token="`+c.Token+`"`)
+}
+
+// OAuth2Credential is an OAuth 2.0 access/refresh token pair, e.g. for
+// GitHub App or GitLab OAuth app installations.
+type OAuth2Credential struct {
+	target       Target
+	AccessToken  string
+	RefreshToken string
+}
+
+func NewOAuth2Credential(target Target, accessToken, refreshToken string) *OAuth2Credential {
+	return &OAuth2Credential{target: target, AccessToken: accessToken, RefreshToken: refreshToken}
+}
+
+func (c *OAuth2Credential) Target() Target { return c.target }
+func (c *OAuth2Credential) Kind() Kind     { return KindOAuth2 }
+
+func (c *OAuth2Credential) SetAuth(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+}
+
+// keyringEntry is the on-disk representation of a single credential in
+// the keyring file pointed to by CREDENTIALS_FILE.
+type keyringEntry struct {
+	Target Target          `json:"target"`
+	Kind   Kind            `json:"kind"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// LoadFromFile reads a JSON array of keyringEntry from path and returns
+// the credentials found for the given target.
+func LoadFromFile(path string, target Target) ([]Credential, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []keyringEntry
+	if err := json.NewDecoder(f).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decoding keyring file %s: %w", path, err)
+	}
+
+	var creds []Credential
+	for _, e := range entries {
+		if e.Target != target {
+			continue
+		}
+		cred, err := decodeEntry(e)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s credential for %s: %w", e.Kind, e.Target, err)
+		}
+		creds = append(creds, cred)
+	}
+	return creds, nil
+}
+
+func decodeEntry(e keyringEntry) (Credential, error) {
+	switch e.Kind {
+	case KindToken:
+		var v struct{ Token string }
+		if err := json.Unmarshal(e.Data, &v); err != nil {
+			return nil, err
+		}
+		return NewTokenCredential(e.Target, v.Token), nil
+	case KindLoginPassword:
+		var v struct{ Login, Password string }
+		if err := json.Unmarshal(e.Data, &v); err != nil {
+			return nil, err
+		}
+		return NewLoginPasswordCredential(e.Target, v.Login, v.Password), nil
+	case KindOAuth1:
+		var v struct{ ConsumerKey, PrivateKey, Token, TokenSecret string }
+		if err := json.Unmarshal(e.Data, &v); err != nil {
+			return nil, err
+		}
+		return NewOAuth1Credential(e.Target, v.ConsumerKey, v.PrivateKey, v.Token, v.TokenSecret), nil
+	case KindOAuth2:
+		var v struct{ AccessToken, RefreshToken string }
+		if err := json.Unmarshal(e.Data, &v); err != nil {
+			return nil, err
+		}
+		return NewOAuth2Credential(e.Target, v.AccessToken, v.RefreshToken), nil
+	default:
+		return nil, fmt.Errorf("unknown credential kind %q", e.Kind)
+	}
+}
+
+// ResolveFromEnv builds the Credential for target using the usual
+// environment variable convention for that target, falling back to the
+// on-disk keyring file named by CREDENTIALS_FILE if the env vars are
+// unset. It returns an error if no credential can be found.
+func ResolveFromEnv(target Target) (Credential, error) {
+	switch target {
+	case TargetJira:
+		if cred := jiraCredentialFromEnv(target); cred != nil {
+			return cred, nil
+		}
+	case TargetGitLab:
+		if tok := getAny("GITLAB_TOKEN", "CI_JOB_TOKEN"); tok != "" {
+			return NewTokenCredential(target, tok), nil
+		}
+	case TargetGitHub:
+		if tok := os.Getenv("GITHUB_TOKEN"); tok != "" {
+			return NewTokenCredential(target, tok), nil
+		}
+	case TargetGitea:
+		if tok := os.Getenv("GITEA_TOKEN"); tok != "" {
+			return NewTokenCredential(target, tok), nil
+		}
+	}
+
+	if path := os.Getenv("CREDENTIALS_FILE"); path != "" {
+		creds, err := LoadFromFile(path, target)
+		if err != nil {
+			return nil, err
+		}
+		if len(creds) > 0 {
+			return creds[0], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s credential found in environment or CREDENTIALS_FILE", target)
+}
+
+// jiraCredentialFromEnv builds the Jira credential named by
+// JIRA_AUTH_MODE. The default ("basic") is the HTTP basic auth pair of
+// JIRA_USER and JIRA_API_TOKEN used by Jira Cloud, or a bearer token when
+// JIRA_USER is unset. "pat" sends JIRA_API_TOKEN as a bearer personal
+// access token. "oauth1" builds an OAuth1Credential carrying the RSA key and
+// token pair jira.NewClientFromEnv needs to sign requests, as required
+// by Jira Server/Data Center.
+func jiraCredentialFromEnv(target Target) Credential {
+	switch os.Getenv("JIRA_AUTH_MODE") {
+	case "oauth1":
+		return NewOAuth1Credential(target,
+			os.Getenv("JIRA_OAUTH_CONSUMER_KEY"),
+			os.Getenv("JIRA_OAUTH_PRIVATE_KEY_PEM"),
+			os.Getenv("JIRA_OAUTH_TOKEN"),
+			os.Getenv("JIRA_OAUTH_TOKEN_SECRET"),
+		)
+	case "pat":
+		if tok := os.Getenv("JIRA_API_TOKEN"); tok != "" {
+			return NewTokenCredential(target, tok)
+		}
+	default:
+		if tok := os.Getenv("JIRA_API_TOKEN"); tok != "" {
+			if user := os.Getenv("JIRA_USER"); user != "" {
+				return NewLoginPasswordCredential(target, user, tok)
+			}
+			return NewTokenCredential(target, tok)
+		}
+	}
+	return nil
+}
+
+func getAny(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+// Package forge abstracts the "where does this merge/pull request live"
+// side of the bot behind a single Forge interface, so the rest of the
+// tool doesn't need to know whether it's talking to GitLab, GitHub, or
+// Gitea/Forgejo.
+package forge
+
+import "fmt"
+
+// PullRequest is the forge-agnostic shape of a Renovate change request,
+// whatever the originating forge calls it (merge request, pull request).
+type PullRequest struct {
+	IID            int
+	Title          string
+	Description    string
+	WebURL         string
+	AuthorUsername string
+	State          string
+}
+
+// Forge is the set of operations the bot needs against a code-hosting
+// backend. Concrete implementations wrap that backend's REST API.
+type Forge interface {
+	// ListOpenPullRequests returns open pull/merge requests authored by
+	// the given username.
+	ListOpenPullRequests(author string) ([]PullRequest, error)
+	// ListPullRequestsByAuthor returns pull/merge requests authored by
+	// the given username in any state, used by the lifecycle sync mode
+	// to notice merges and closes.
+	ListPullRequestsByAuthor(author string) ([]PullRequest, error)
+	// GetComments returns the existing comment bodies on a pull/merge
+	// request, used to look for an already-linked Jira key.
+	GetComments(iid int) ([]string, error)
+	// AddComment posts a new comment on a pull/merge request.
+	AddComment(iid int, body string) error
+}
+
+// Kind identifies which Forge implementation to use, selected by the
+// FORGE_KIND environment variable.
+type Kind string
+
+const (
+	KindGitLab Kind = "gitlab"
+	KindGitHub Kind = "github"
+	KindGitea  Kind = "gitea"
+)
+
+// ErrUnknownKind is returned by New when FORGE_KIND doesn't match a
+// known implementation.
+type ErrUnknownKind struct {
+	Kind Kind
+}
+
+func (e ErrUnknownKind) Error() string {
+	return fmt.Sprintf("unknown FORGE_KIND %q (want gitlab, github, or gitea)", e.Kind)
+}
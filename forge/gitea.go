@@ -0,0 +1,101 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/n3amil/renovate-jira-bot/auth"
+)
+
+// GiteaForge talks to the Gitea/Forgejo REST API (v1), which mirrors
+// GitHub's shape closely enough to reuse the same JSON fields.
+type GiteaForge struct {
+	baseURL string // e.g. https://gitea.example.com/api/v1
+	owner   string
+	repo    string
+	cred    auth.Credential
+	client  *http.Client
+}
+
+// NewGiteaForge builds a GiteaForge for the given owner/repo against a
+// Gitea or Forgejo instance's API root.
+func NewGiteaForge(baseURL, owner, repo string, cred auth.Credential) *GiteaForge {
+	return &GiteaForge{
+		baseURL: baseURL,
+		owner:   owner,
+		repo:    repo,
+		cred:    cred,
+		client:  http.DefaultClient,
+	}
+}
+
+type giteaPullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"html_url"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (f *GiteaForge) ListOpenPullRequests(author string) ([]PullRequest, error) {
+	return f.listPullRequests(author, "open")
+}
+
+func (f *GiteaForge) ListPullRequestsByAuthor(author string) ([]PullRequest, error) {
+	return f.listPullRequests(author, "all")
+}
+
+func (f *GiteaForge) listPullRequests(author, state string) ([]PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls?state=%s", f.baseURL, f.owner, f.repo, state)
+
+	var prs []giteaPullRequest
+	if err := doJSON(f.client, f.cred, "GET", url, nil, &prs); err != nil {
+		return nil, err
+	}
+
+	var out []PullRequest
+	for _, pr := range prs {
+		if pr.User.Login != author {
+			continue
+		}
+		state := pr.State
+		if pr.Merged {
+			state = "merged"
+		}
+		out = append(out, PullRequest{
+			IID:            pr.Number,
+			Title:          pr.Title,
+			Description:    pr.Body,
+			WebURL:         pr.URL,
+			AuthorUsername: pr.User.Login,
+			State:          state,
+		})
+	}
+	return out, nil
+}
+
+func (f *GiteaForge) GetComments(iid int) ([]string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", f.baseURL, f.owner, f.repo, iid)
+
+	var comments []struct {
+		Body string `json:"body"`
+	}
+	if err := doJSON(f.client, f.cred, "GET", url, nil, &comments); err != nil {
+		return nil, err
+	}
+
+	bodies := make([]string, len(comments))
+	for i, c := range comments {
+		bodies[i] = c.Body
+	}
+	return bodies, nil
+}
+
+func (f *GiteaForge) AddComment(iid int, body string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", f.baseURL, f.owner, f.repo, iid)
+	return doJSON(f.client, f.cred, "POST", url, map[string]string{"body": body}, nil)
+}
@@ -0,0 +1,109 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/n3amil/renovate-jira-bot/auth"
+)
+
+// GitHubForge talks to the GitHub REST API (v3) for a single repository.
+// Pull requests are fetched from the /pulls endpoint (first page only)
+// and filtered client-side down to PRs authored by the given user.
+type GitHubForge struct {
+	baseURL string // e.g. https://api.github.com
+	owner   string
+	repo    string
+	cred    auth.Credential
+	client  *http.Client
+}
+
+// NewGitHubForge builds a GitHubForge for the given owner/repo, using
+// baseURL as the API root (api.github.com for github.com, or a GitHub
+// Enterprise Server's API URL).
+func NewGitHubForge(baseURL, owner, repo string, cred auth.Credential) *GitHubForge {
+	return &GitHubForge{
+		baseURL: baseURL,
+		owner:   owner,
+		repo:    repo,
+		cred:    cred,
+		client:  http.DefaultClient,
+	}
+}
+
+type githubPullRequest struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	Body     string  `json:"body"`
+	HTMLURL  string  `json:"html_url"`
+	State    string  `json:"state"`
+	MergedAt *string `json:"merged_at"`
+	User     struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (f *GitHubForge) ListOpenPullRequests(author string) ([]PullRequest, error) {
+	return f.listPullRequests(author, "open")
+}
+
+func (f *GitHubForge) ListPullRequestsByAuthor(author string) ([]PullRequest, error) {
+	return f.listPullRequests(author, "all")
+}
+
+func (f *GitHubForge) listPullRequests(author, state string) ([]PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls?state=%s", f.baseURL, f.owner, f.repo, state)
+
+	var prs []githubPullRequest
+	if err := doJSON(f.client, f.cred, "GET", url, nil, &prs); err != nil {
+		return nil, err
+	}
+
+	var out []PullRequest
+	for _, pr := range prs {
+		if pr.User.Login != author {
+			continue
+		}
+		out = append(out, PullRequest{
+			IID:            pr.Number,
+			Title:          pr.Title,
+			Description:    pr.Body,
+			WebURL:         pr.HTMLURL,
+			AuthorUsername: pr.User.Login,
+			State:          prState(pr),
+		})
+	}
+	return out, nil
+}
+
+// prState maps GitHub's open/closed state plus merged_at into a
+// three-way open/merged/closed state; the lifecycle sync acts only on
+// merged and closed.
+func prState(pr githubPullRequest) string {
+	if pr.MergedAt != nil {
+		return "merged"
+	}
+	return pr.State
+}
+
+func (f *GitHubForge) GetComments(iid int) ([]string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", f.baseURL, f.owner, f.repo, iid)
+
+	var comments []struct {
+		Body string `json:"body"`
+	}
+	if err := doJSON(f.client, f.cred, "GET", url, nil, &comments); err != nil {
+		return nil, err
+	}
+
+	bodies := make([]string, len(comments))
+	for i, c := range comments {
+		bodies[i] = c.Body
+	}
+	return bodies, nil
+}
+
+func (f *GitHubForge) AddComment(iid int, body string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", f.baseURL, f.owner, f.repo, iid)
+	return doJSON(f.client, f.cred, "POST", url, map[string]string{"body": body}, nil)
+}
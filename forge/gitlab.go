@@ -0,0 +1,76 @@
+package forge
+
+import (
+	"github.com/n3amil/renovate-jira-bot/auth"
+	"github.com/n3amil/renovate-jira-bot/gitlabclient"
+)
+
+// GitLabForge talks to the GitLab REST API (v4) for a single project,
+// via gitlabclient so listings are fully paginated and resilient to
+// rate-limiting.
+type GitLabForge struct {
+	client *gitlabclient.Client
+}
+
+// NewGitLabForge builds a GitLabForge for the given instance URL and
+// project ID, authenticating requests with cred.
+func NewGitLabForge(baseURL, projectID string, cred auth.Credential) *GitLabForge {
+	return &GitLabForge{client: gitlabclient.New(baseURL, projectID, cred)}
+}
+
+func (f *GitLabForge) ListOpenPullRequests(author string) ([]PullRequest, error) {
+	mrs, err := f.client.ListOpenMergeRequestsByAuthor(author)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PullRequest, len(mrs))
+	for i, mr := range mrs {
+		out[i] = PullRequest{
+			IID:            mr.IID,
+			Title:          mr.Title,
+			Description:    mr.Description,
+			WebURL:         mr.WebURL,
+			AuthorUsername: mr.Author.Username,
+			State:          mr.State,
+		}
+	}
+	return out, nil
+}
+
+func (f *GitLabForge) ListPullRequestsByAuthor(author string) ([]PullRequest, error) {
+	mrs, err := f.client.ListMergeRequestsByAuthor(author)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PullRequest, len(mrs))
+	for i, mr := range mrs {
+		out[i] = PullRequest{
+			IID:            mr.IID,
+			Title:          mr.Title,
+			Description:    mr.Description,
+			WebURL:         mr.WebURL,
+			AuthorUsername: mr.Author.Username,
+			State:          mr.State,
+		}
+	}
+	return out, nil
+}
+
+func (f *GitLabForge) GetComments(iid int) ([]string, error) {
+	notes, err := f.client.ListMergeRequestNotes(iid)
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := make([]string, len(notes))
+	for i, n := range notes {
+		bodies[i] = n.Body
+	}
+	return bodies, nil
+}
+
+func (f *GitLabForge) AddComment(iid int, body string) error {
+	return f.client.AddNote(iid, body)
+}
@@ -0,0 +1,37 @@
+package forge
+
+import (
+	"os"
+
+	"github.com/n3amil/renovate-jira-bot/auth"
+)
+
+// New builds the Forge selected by kind, reading the connection details
+// it needs (instance URL, project/owner/repo) from the environment.
+func New(kind Kind, cred auth.Credential) (Forge, error) {
+	switch kind {
+	case KindGitLab:
+		baseURL := getEnv("GITLAB_URL", os.Getenv("CI_SERVER_URL"))
+		projectID := getEnv("GITLAB_PROJECT_ID", os.Getenv("CI_PROJECT_ID"))
+		return NewGitLabForge(baseURL, projectID, cred), nil
+	case KindGitHub:
+		baseURL := getEnv("GITHUB_API_URL", "https://api.github.com")
+		owner := os.Getenv("GITHUB_OWNER")
+		repo := os.Getenv("GITHUB_REPO")
+		return NewGitHubForge(baseURL, owner, repo, cred), nil
+	case KindGitea:
+		baseURL := os.Getenv("GITEA_URL")
+		owner := os.Getenv("GITEA_OWNER")
+		repo := os.Getenv("GITEA_REPO")
+		return NewGiteaForge(baseURL, owner, repo, cred), nil
+	default:
+		return nil, ErrUnknownKind{Kind: kind}
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,222 @@
+// Package gitlabclient is a small internal GitLab REST (v4) client that
+// handles the concerns the bot's original ad-hoc http.Get calls missed:
+// following pagination to completion and retrying rate-limited or
+// transient server errors with backoff.
+package gitlabclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/n3amil/renovate-jira-bot/auth"
+)
+
+// Client talks to a single GitLab project's REST API.
+type Client struct {
+	baseURL    string
+	projectID  string
+	cred       auth.Credential
+	httpClient *http.Client
+
+	// maxRetries bounds the retry/backoff loop for 429s and 5xxs.
+	maxRetries int
+}
+
+// New builds a Client for the given GitLab instance and project,
+// authenticating requests with cred.
+func New(baseURL, projectID string, cred auth.Credential) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		projectID:  projectID,
+		cred:       cred,
+		httpClient: http.DefaultClient,
+		maxRetries: 5,
+	}
+}
+
+type MergeRequest struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+	State       string `json:"state"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+type Note struct {
+	Body string `json:"body"`
+}
+
+// ListOpenMergeRequestsByAuthor returns every open merge request
+// authored by username, following pagination to the last page.
+func (c *Client) ListOpenMergeRequestsByAuthor(username string) ([]MergeRequest, error) {
+	path := fmt.Sprintf("/api/v4/projects/%s/merge_requests?state=opened&author_username=%s", c.projectID, username)
+	return listAll[MergeRequest](c, path)
+}
+
+// ListMergeRequestsByAuthor returns every merge request authored by
+// username regardless of state (opened, merged, or closed), following
+// pagination to the last page.
+func (c *Client) ListMergeRequestsByAuthor(username string) ([]MergeRequest, error) {
+	path := fmt.Sprintf("/api/v4/projects/%s/merge_requests?state=all&author_username=%s", c.projectID, username)
+	return listAll[MergeRequest](c, path)
+}
+
+// ListMergeRequestNotes returns every comment on a merge request,
+// following pagination to the last page.
+func (c *Client) ListMergeRequestNotes(iid int) ([]Note, error) {
+	path := fmt.Sprintf("/api/v4/projects/%s/merge_requests/%d/notes", c.projectID, iid)
+	return listAll[Note](c, path)
+}
+
+// AddNote posts a new comment on a merge request.
+func (c *Client) AddNote(iid int, body string) error {
+	path := fmt.Sprintf("/api/v4/projects/%s/merge_requests/%d/notes", c.projectID, iid)
+	return c.do("POST", path, map[string]string{"body": body}, nil)
+}
+
+// listAll follows the `Link: rel="next"` header until exhausted,
+// accumulating every page's items into a single slice.
+func listAll[T any](c *Client, path string) ([]T, error) {
+	var all []T
+	next := c.baseURL + path
+
+	for next != "" {
+		var page []T
+		link, err := c.getPage(next, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		next = link
+	}
+	return all, nil
+}
+
+// getPage fetches url and returns the `rel="next"` link for pagination,
+// or "" once the last page has been reached.
+func (c *Client) getPage(url string, out interface{}) (nextURL string, err error) {
+	resp, err := c.doWithRetry("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return parseNextLink(resp.Header.Get("Link")), nil
+}
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	resp, err := c.doWithRetry(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// doWithRetry performs a single logical request, retrying 429 and 5xx
+// responses with exponential backoff honoring Retry-After when GitLab
+// sends one. The caller owns closing the returned response body.
+func (c *Client) doWithRetry(method, url string, body interface{}) (*http.Response, error) {
+	var bodyBytes []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyBytes = b
+	}
+
+	var lastErr error
+	backoff := time.Second
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		var reqBody io.Reader
+		if bodyBytes != nil {
+			reqBody = bytes.NewReader(bodyBytes)
+		}
+
+		req, err := http.NewRequest(method, url, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if bodyBytes != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		c.cred.SetAuth(req)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("GitLab request to %s failed: %w", url, err)
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			wait := retryAfter(resp.Header, backoff)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("GitLab returned %d for %s", resp.StatusCode, url)
+			time.Sleep(wait)
+			backoff *= 2
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("GitLab error: %s returned %d: %s", url, resp.StatusCode, string(respBody))
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("giving up after %d retries: %w", c.maxRetries, lastErr)
+}
+
+// retryAfter honors GitLab's Retry-After header (seconds) when present,
+// falling back to the exponential backoff value otherwise.
+func retryAfter(h http.Header, fallback time.Duration) time.Duration {
+	if ra := h.Get("Retry-After"); ra != "" {
+		if secs, err := strconv.Atoi(ra); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return fallback
+}
+
+// parseNextLink extracts the rel="next" URL from a GitHub/GitLab-style
+// Link header, returning "" if there is no next page.
+func parseNextLink(header string) string {
+	if header == "" {
+		return ""
+	}
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		url := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+		for _, seg := range segments[1:] {
+			if strings.TrimSpace(seg) == `rel="next"` {
+				return url
+			}
+		}
+	}
+	return ""
+}
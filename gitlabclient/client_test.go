@@ -0,0 +1,116 @@
+package gitlabclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/n3amil/renovate-jira-bot/auth"
+)
+
+func TestParseNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "no header",
+			header: "",
+			want:   "",
+		},
+		{
+			name:   "next and last",
+			header: `<https://gitlab.example.com/api/v4/projects/1/merge_requests?page=2>; rel="next", <https://gitlab.example.com/api/v4/projects/1/merge_requests?page=5>; rel="last"`,
+			want:   "https://gitlab.example.com/api/v4/projects/1/merge_requests?page=2",
+		},
+		{
+			name:   "last page only",
+			header: `<https://gitlab.example.com/api/v4/projects/1/merge_requests?page=1>; rel="first", <https://gitlab.example.com/api/v4/projects/1/merge_requests?page=1>; rel="prev"`,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNextLink(tt.header); got != tt.want {
+				t.Errorf("parseNextLink(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAllFollowsPagination(t *testing.T) {
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/1/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"iid":2,"title":"second"}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+"http://"+r.Host+"/api/v4/projects/1/merge_requests?page=2"+`>; rel="next"`)
+		w.Write([]byte(`[{"iid":1,"title":"first"}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(srv.URL, "1", auth.NewTokenCredential(auth.TargetGitLab, "tok"))
+	got, err := c.ListOpenMergeRequestsByAuthor("renovate[bot]")
+	if err != nil {
+		t.Fatalf("ListOpenMergeRequestsByAuthor() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d merge requests, want 2", len(got))
+	}
+	if got[0].IID != 1 || got[1].IID != 2 {
+		t.Errorf("got IIDs %d, %d, want 1, 2", got[0].IID, got[1].IID)
+	}
+}
+
+func TestDoWithRetryRetriesRateLimitedRequests(t *testing.T) {
+	attempts := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/1/merge_requests/1/notes", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(srv.URL, "1", auth.NewTokenCredential(auth.TargetGitLab, "tok"))
+	if err := c.AddNote(1, "hello"); err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2 (one 429 then one success)", attempts)
+	}
+}
+
+func TestDoWithRetryGivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/1/merge_requests/1/notes", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(srv.URL, "1", auth.NewTokenCredential(auth.TargetGitLab, "tok"))
+	c.maxRetries = 2
+
+	if err := c.AddNote(1, "hello"); err == nil {
+		t.Fatal("AddNote() error = nil, want an error after exhausting retries")
+	}
+	if attempts != 3 {
+		t.Errorf("got %d attempts, want 3 (initial + 2 retries)", attempts)
+	}
+}
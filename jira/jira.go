@@ -0,0 +1,257 @@
+// Package jira creates and inspects Jira issues for Renovate merge/pull
+// requests.
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/n3amil/renovate-jira-bot/adf"
+	"github.com/n3amil/renovate-jira-bot/auth"
+	"github.com/n3amil/renovate-jira-bot/renovate"
+)
+
+// Client creates Jira issues against a single Jira project.
+type Client struct {
+	baseURL    string
+	projectKey string
+	apiVersion string // "2" or "3"
+	cred       auth.Credential
+	client     *http.Client
+}
+
+// NewClient builds a Jira Client for the given instance URL and project
+// key, authenticating requests with cred.
+func NewClient(baseURL, projectKey string, cred auth.Credential) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		projectKey: projectKey,
+		apiVersion: defaultAPIVersion,
+		cred:       cred,
+		client:     http.DefaultClient,
+	}
+}
+
+// defaultAPIVersion is used when JIRA_API_VERSION is unset. Jira Server/
+// Data Center instances are v2; Jira Cloud requires v3.
+const defaultAPIVersion = "2"
+
+// apiVersionFromEnv reads JIRA_API_VERSION, defaulting to "2", and
+// rejects anything else so a typo fails fast instead of silently hitting
+// the wrong REST path.
+func apiVersionFromEnv() (string, error) {
+	v := getEnvOr("JIRA_API_VERSION", defaultAPIVersion)
+	if v != "2" && v != "3" {
+		return "", fmt.Errorf("unknown JIRA_API_VERSION %q (want 2 or 3)", v)
+	}
+	return v, nil
+}
+
+// NewClientFromEnv builds a Client for the given instance URL and
+// project key, resolving its credential the same way every other forge
+// in this tool does: through auth.ResolveFromEnv, which picks the
+// authentication mode named by JIRA_AUTH_MODE (default "basic") and
+// falls back to the CREDENTIALS_FILE keyring if the mode's environment
+// variables aren't set.
+func NewClientFromEnv(baseURL, projectKey string) (*Client, error) {
+	apiVersion, err := apiVersionFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := auth.ResolveFromEnv(auth.TargetJira)
+	if err != nil {
+		return nil, err
+	}
+
+	if oauthCred, ok := cred.(*auth.OAuth1Credential); ok {
+		key, err := parseRSAPrivateKeyPEM(oauthCred.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JIRA_OAUTH_PRIVATE_KEY_PEM: %w", err)
+		}
+		signer := &oauth1Signer{
+			consumerKey: oauthCred.ConsumerKey,
+			privateKey:  key,
+			token:       oauthCred.Token,
+			tokenSecret: oauthCred.TokenSecret,
+		}
+		return &Client{
+			baseURL:    baseURL,
+			projectKey: projectKey,
+			apiVersion: apiVersion,
+			client:     &http.Client{Transport: &oauth1Transport{signer: signer}},
+		}, nil
+	}
+
+	c := NewClient(baseURL, projectKey, cred)
+	c.apiVersion = apiVersion
+	return c, nil
+}
+
+// IssueDescription is everything CreateIssue needs to render a
+// description, whether as a plain string (API v2) or an ADF document
+// (API v3).
+type IssueDescription struct {
+	Text     string
+	MRURL    string
+	Packages []renovate.Package
+}
+
+// CreateIssue creates an issue of issueType in the configured project
+// with the given summary, description, and labels. In dryRun mode it
+// only logs what would be created and returns a placeholder key.
+func (c *Client) CreateIssue(summary string, description IssueDescription, issueType string, labels []string, dryRun bool) (string, error) {
+	if dryRun {
+		fmt.Printf("[DRY-RUN] Would create Jira issue:\n  Title: %s\n  Desc: %s\n  Type: %s\n  Labels: %v\n\n", summary, description.Text, issueType, labels)
+		return "DRY-123", nil
+	}
+
+	fields := map[string]interface{}{
+		"project": map[string]string{
+			"key": c.projectKey,
+		},
+		"summary":     summary,
+		"description": c.renderDescription(description),
+		"labels":      labels,
+		"issuetype": map[string]string{
+			"name": issueType,
+		},
+	}
+	if err := mergeExtraFields(fields); err != nil {
+		return "", err
+	}
+	var respData struct {
+		Key string `json:"key"`
+	}
+	path := fmt.Sprintf("/rest/api/%s/issue", c.apiVersion)
+	if err := c.request("POST", path, map[string]interface{}{"fields": fields}, &respData); err != nil {
+		return "", err
+	}
+	return respData.Key, nil
+}
+
+// renderDescription builds the `description` field value for desc: a
+// plain string under API v2 (desc.Text as rendered by the Jira template,
+// packages and all), or an ADF document under API v3, where Jira Cloud
+// rejects a bare string for rich-text fields. The v3 document is built
+// structurally from desc.MRURL/desc.Packages rather than by wrapping
+// desc.Text, since that text already embeds the package list and MR URL
+// as newline-separated plain text that ADF can't represent as a single
+// text node.
+func (c *Client) renderDescription(desc IssueDescription) interface{} {
+	if c.apiVersion != "3" {
+		return desc.Text
+	}
+
+	content := []adf.Node{adf.Paragraph(adf.Text("Renovate update from "), adf.Link(desc.MRURL, desc.MRURL))}
+
+	if len(desc.Packages) > 0 {
+		items := make([]adf.Node, len(desc.Packages))
+		for i, pkg := range desc.Packages {
+			items[i] = adf.ListItem(adf.Paragraph(adf.Text(fmt.Sprintf("%s: %s -> %s (%s, %s)", pkg.Name, pkg.CurrentVersion, pkg.NewVersion, pkg.UpdateType, pkg.Manager))))
+		}
+		content = append(content, adf.BulletList(items...))
+	}
+
+	return adf.Doc(content...)
+}
+
+// request performs an authenticated Jira REST call, decoding a JSON
+// response into out (if non-nil) and treating any status >= 300 as an
+// error with the response body attached for context.
+func (c *Client) request(method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cred != nil {
+		c.cred.SetAuth(req)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Jira request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Jira error: %s %s returned %d: %s", method, path, resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// FindKey looks for a projectKey-NNN issue key in text, e.g. in an MR's
+// title, description, or comments.
+func FindKey(text, projectKey string) (string, bool) {
+	jiraRegex := regexp.MustCompile(fmt.Sprintf(`%s-\d+`, regexp.QuoteMeta(projectKey)))
+	match := jiraRegex.FindString(text)
+	return match, match != ""
+}
+
+// mergeExtraFields merges the JSON object in JIRA_EXTRA_FIELDS_JSON (if
+// set) into fields, for custom fields like epic links, components, or
+// priority that this tool has no first-class support for.
+func mergeExtraFields(fields map[string]interface{}) error {
+	raw := os.Getenv("JIRA_EXTRA_FIELDS_JSON")
+	if raw == "" {
+		return nil
+	}
+
+	var extra map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &extra); err != nil {
+		return fmt.Errorf("parsing JIRA_EXTRA_FIELDS_JSON: %w", err)
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	return nil
+}
+
+// defaultIssueType is used when JIRA_ISSUETYPE_BY_UPDATE_TYPE
+// doesn't override a given Renovate update type.
+const defaultIssueType = "Task"
+
+// IssueTypeForUpdateType resolves a Renovate update type (major, minor,
+// patch) to a Jira issue type name, using the JIRA_ISSUETYPE_BY_UPDATE_TYPE
+// environment variable (a comma-separated list of updateType=IssueType
+// pairs, e.g. "major=Story,minor=Task,patch=Sub-task"). Falls back to
+// "Task" when unset or when updateType has no entry.
+func IssueTypeForUpdateType(updateType string) string {
+	raw := os.Getenv("JIRA_ISSUETYPE_BY_UPDATE_TYPE")
+	if raw == "" {
+		return defaultIssueType
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(k), updateType) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return defaultIssueType
+}
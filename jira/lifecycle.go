@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/n3amil/renovate-jira-bot/adf"
+)
+
+// Transition is one of the workflow transitions available on an issue,
+// as returned by GET .../issue/{key}/transitions.
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Transitions lists the workflow transitions currently available on
+// issueKey.
+func (c *Client) Transitions(issueKey string) ([]Transition, error) {
+	var resp struct {
+		Transitions []Transition `json:"transitions"`
+	}
+	path := fmt.Sprintf("/rest/api/%s/issue/%s/transitions", c.apiVersion, issueKey)
+	if err := c.request("GET", path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Transitions, nil
+}
+
+// TransitionTo moves issueKey through the named workflow transition
+// (resolved case-insensitively against the issue's available
+// transitions, since transition names but not IDs are stable across
+// Jira configurations). In dryRun mode it only logs what it would do.
+func (c *Client) TransitionTo(issueKey, name string, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("[DRY-RUN] Would transition %s to %q\n", issueKey, name)
+		return nil
+	}
+
+	transitions, err := c.Transitions(issueKey)
+	if err != nil {
+		return fmt.Errorf("listing transitions for %s: %w", issueKey, err)
+	}
+
+	var id string
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, name) {
+			id = t.ID
+			break
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("no %q transition available on %s", name, issueKey)
+	}
+
+	body := map[string]interface{}{
+		"transition": map[string]string{"id": id},
+	}
+	path := fmt.Sprintf("/rest/api/%s/issue/%s/transitions", c.apiVersion, issueKey)
+	return c.request("POST", path, body, nil)
+}
+
+// AddComment posts a comment on issueKey: a plain string under API v2,
+// or an ADF document under API v3, since Jira Cloud rejects a bare
+// string for rich-text fields. In dryRun mode it only logs what it
+// would do.
+func (c *Client) AddComment(issueKey, body string, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("[DRY-RUN] Would comment on %s: %s\n", issueKey, body)
+		return nil
+	}
+	path := fmt.Sprintf("/rest/api/%s/issue/%s/comment", c.apiVersion, issueKey)
+	return c.request("POST", path, map[string]interface{}{"body": c.renderComment(body)}, nil)
+}
+
+// AddLabels adds labels to issueKey without disturbing any it already
+// has. In dryRun mode it only logs what it would do.
+func (c *Client) AddLabels(issueKey string, labels []string, dryRun bool) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("[DRY-RUN] Would add labels %v to %s\n", labels, issueKey)
+		return nil
+	}
+
+	adds := make([]map[string]string, len(labels))
+	for i, l := range labels {
+		adds[i] = map[string]string{"add": l}
+	}
+	body := map[string]interface{}{
+		"update": map[string]interface{}{
+			"labels": adds,
+		},
+	}
+	path := fmt.Sprintf("/rest/api/%s/issue/%s", c.apiVersion, issueKey)
+	return c.request("PUT", path, body, nil)
+}
+
+// renderComment builds the `body` field value for a comment's text: a
+// plain string under API v2, or a single-paragraph ADF document under
+// API v3.
+func (c *Client) renderComment(text string) interface{} {
+	if c.apiVersion != "3" {
+		return text
+	}
+	return adf.Doc(adf.Paragraph(adf.Text(text)))
+}
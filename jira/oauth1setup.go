@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RunOAuth1Setup walks the OAuth 1.0a three-legged dance against a Jira
+// Server/Data Center instance: it requests a temporary token, prints the
+// authorize URL for the user to visit, reads back the oauth_verifier
+// Jira shows once authorized, then exchanges the verified request token
+// for a permanent access token pair. It prints the resulting
+// JIRA_OAUTH_TOKEN / JIRA_OAUTH_TOKEN_SECRET for the caller to store.
+func RunOAuth1Setup(baseURL, consumerKey, privateKeyPEM string, in io.Reader, out io.Writer) error {
+	key, err := parseRSAPrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return err
+	}
+	signer := &oauth1Signer{consumerKey: consumerKey, privateKey: key}
+	client := &http.Client{Transport: &oauth1Transport{signer: signer}}
+
+	reqToken, reqSecret, err := fetchToken(client, baseURL+"/plugins/servlet/oauth/request-token")
+	if err != nil {
+		return fmt.Errorf("requesting temporary token: %w", err)
+	}
+
+	fmt.Fprintf(out, "Open this URL in a browser and authorize the application:\n\n  %s/plugins/servlet/oauth/authorize?oauth_token=%s\n\n", baseURL, url.QueryEscape(reqToken))
+	fmt.Fprint(out, "Paste the verification code Jira shows after you authorize it: ")
+	verifier, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading oauth_verifier: %w", err)
+	}
+
+	signer.token = reqToken
+	signer.tokenSecret = reqSecret
+	signer.verifier = strings.TrimSpace(verifier)
+	accessToken, accessSecret, err := fetchToken(client, baseURL+"/plugins/servlet/oauth/access-token")
+	if err != nil {
+		return fmt.Errorf("exchanging for access token: %w", err)
+	}
+
+	fmt.Fprintf(out, "\nSet these environment variables:\n\n  JIRA_AUTH_MODE=oauth1\n  JIRA_OAUTH_CONSUMER_KEY=%s\n  JIRA_OAUTH_TOKEN=%s\n  JIRA_OAUTH_TOKEN_SECRET=%s\n", consumerKey, accessToken, accessSecret)
+	return nil
+}
+
+// fetchToken POSTs to a Jira OAuth1 token endpoint (request-token or
+// access-token) and parses the oauth_token/oauth_token_secret pair out
+// of the form-encoded response body.
+func fetchToken(client *http.Client, endpoint string) (token, secret string, err error) {
+	resp, err := client.Post(endpoint, "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", "", fmt.Errorf("%s returned %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+
+	values, err := url.ParseQuery(strings.TrimSpace(string(body)))
+	if err != nil {
+		return "", "", err
+	}
+
+	token = values.Get("oauth_token")
+	secret = values.Get("oauth_token_secret")
+	if token == "" || secret == "" {
+		return "", "", fmt.Errorf("%s did not return an oauth_token pair: %s", endpoint, string(body))
+	}
+	return token, secret, nil
+}
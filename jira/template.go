@@ -0,0 +1,77 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/n3amil/renovate-jira-bot/renovate"
+)
+
+// TemplateMR is the subset of a merge/pull request exposed to issue
+// templates as `.MR`.
+type TemplateMR struct {
+	IID         int
+	Title       string
+	Description string
+	WebURL      string
+}
+
+// TemplateContext is the data available to JIRA_SUMMARY_TEMPLATE and
+// JIRA_DESCRIPTION_TEMPLATE.
+type TemplateContext struct {
+	MR         TemplateMR
+	Packages   []renovate.Package
+	UpdateType string
+	Repo       string
+	Now        time.Time
+}
+
+// defaultSummaryTemplate is used when JIRA_SUMMARY_TEMPLATE is unset. It
+// renders "[renovate] bump <name> from <old> to <new> (<type>)" for a
+// single package, "[renovate] bump N packages (<type>)" for a grouped
+// update, and the MR title alone when no Renovate table was parsed.
+const defaultSummaryTemplate = `{{if gt (len .Packages) 1}}[renovate] bump {{len .Packages}} packages ({{.UpdateType}}){{else if .Packages}}[renovate] bump {{(index .Packages 0).Name}} from {{(index .Packages 0).CurrentVersion}} to {{(index .Packages 0).NewVersion}} ({{(index .Packages 0).UpdateType}}){{else}}{{.MR.Title}}{{end}}`
+
+// defaultDescriptionTemplate is used when JIRA_DESCRIPTION_TEMPLATE is
+// unset: a "Renovate update from <MR URL>" line, followed by one line per
+// package when a Renovate table was parsed.
+const defaultDescriptionTemplate = `Renovate update from {{.MR.WebURL}}
+{{if .Packages}}
+Packages:
+{{range .Packages}}- {{.Name}}: {{.CurrentVersion}} -> {{.NewVersion}} ({{.UpdateType}}, {{.Manager}})
+{{end}}{{end}}`
+
+// RenderSummary renders JIRA_SUMMARY_TEMPLATE (or the built-in default)
+// against ctx.
+func RenderSummary(ctx TemplateContext) (string, error) {
+	return renderTemplate(getEnvOr("JIRA_SUMMARY_TEMPLATE", defaultSummaryTemplate), ctx)
+}
+
+// RenderDescription renders JIRA_DESCRIPTION_TEMPLATE (or the built-in
+// default) against ctx.
+func RenderDescription(ctx TemplateContext) (string, error) {
+	return renderTemplate(getEnvOr("JIRA_DESCRIPTION_TEMPLATE", defaultDescriptionTemplate), ctx)
+}
+
+func renderTemplate(text string, ctx TemplateContext) (string, error) {
+	tmpl, err := template.New("jira").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing Jira issue template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("rendering Jira issue template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func getEnvOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -1,26 +1,17 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
-)
+	"time"
 
-type MergeRequest struct {
-	IID         int    `json:"iid"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	WebURL      string `json:"web_url"`
-	Author      struct {
-		Username string `json:"username"`
-	} `json:"author"`
-}
+	"github.com/n3amil/renovate-jira-bot/auth"
+	"github.com/n3amil/renovate-jira-bot/forge"
+	"github.com/n3amil/renovate-jira-bot/jira"
+	"github.com/n3amil/renovate-jira-bot/renovate"
+)
 
 func getEnv(key, fallback string) string {
 	v := os.Getenv(key)
@@ -30,48 +21,14 @@ func getEnv(key, fallback string) string {
 	return v
 }
 
-func getRenovateMRs() ([]MergeRequest, error) {
-	projectID := getEnv("GITLAB_PROJECT_ID", os.Getenv("CI_PROJECT_ID"))
-	gitlabURL := getEnv("GITLAB_URL", os.Getenv("CI_SERVER_URL"))
-	token := getEnv("GITLAB_TOKEN", os.Getenv("CI_JOB_TOKEN"))
-	renovateUsername := getEnv("RENOVATE_USERNAME", "renovate[bot]")
-	url := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests?state=opened", gitlabURL, projectID)
-
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("PRIVATE-TOKEN", token)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Gitlab error: %d", resp.StatusCode)
-	}
-	defer resp.Body.Close()
-
-	var mrs []MergeRequest
-	if err := json.NewDecoder(resp.Body).Decode(&mrs); err != nil {
-		return nil, err
-	}
-
-	var renovateMRs []MergeRequest
-	for _, mr := range mrs {
-		if mr.Author.Username == renovateUsername {
-			renovateMRs = append(renovateMRs, mr)
-		}
-	}
-	return renovateMRs, nil
-}
-
-func hasJiraKey(text string) (string, bool) {
-	projectKey := os.Getenv("JIRA_PROJECT_KEY")
-	jiraRegex := regexp.MustCompile(fmt.Sprintf(`%s-\d+`, regexp.QuoteMeta(projectKey)))
-	match := jiraRegex.FindString(text)
-	return match, match != ""
-}
-
-func containsKeyword(mr MergeRequest) bool {
+func containsKeyword(pr forge.PullRequest) bool {
 	keywords := strings.Split(os.Getenv("KEYWORDS_TO_SKIP"), ",")
 	for _, keyword := range keywords {
-		if containsIgnoreCase(mr.Title, keyword) || containsIgnoreCase(mr.Description, keyword) {
-			fmt.Printf("MR %d contains keywords to be skipped, skipping.\n", mr.IID)
+		if keyword == "" {
+			continue
+		}
+		if containsIgnoreCase(pr.Title, keyword) || containsIgnoreCase(pr.Description, keyword) {
+			fmt.Printf("MR %d contains keywords to be skipped, skipping.\n", pr.IID)
 			return true
 		}
 	}
@@ -82,158 +39,255 @@ func containsIgnoreCase(text, substr string) bool {
 	return strings.Contains(strings.ToLower(text), strings.ToLower(substr))
 }
 
-func mrHasLinkedJira(mr MergeRequest, projectID, token, gitlabURL string) (bool, error) {
-	// Check title and description first
-	if _, found := hasJiraKey(mr.Title); found {
-		return true, nil
+// findLinkedJiraKey returns the Jira key already referenced in pr's
+// title, description, or comments, if any.
+func findLinkedJiraKey(f forge.Forge, pr forge.PullRequest, projectKey string) (string, bool, error) {
+	if key, found := jira.FindKey(pr.Title, projectKey); found {
+		return key, true, nil
 	}
-	if _, found := hasJiraKey(mr.Description); found {
-		return true, nil
+	if key, found := jira.FindKey(pr.Description, projectKey); found {
+		return key, true, nil
 	}
 
-	// Then check comments
-	url := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes", gitlabURL, projectID, mr.IID)
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("PRIVATE-TOKEN", token)
-	resp, err := http.DefaultClient.Do(req)
+	comments, err := f.GetComments(pr.IID)
 	if err != nil {
-		return false, err
+		return "", false, err
 	}
-	defer resp.Body.Close()
+	for _, body := range comments {
+		if key, found := jira.FindKey(body, projectKey); found {
+			return key, true, nil
+		}
+	}
+	return "", false, nil
+}
 
-	var notes []struct {
-		Body string `json:"body"`
+// setup resolves the forge and Jira client shared by every sync mode.
+func setup() (forge.Forge, *jira.Client, error) {
+	forgeKind := forge.Kind(getEnv("FORGE_KIND", string(forge.KindGitLab)))
+	forgeCred, err := auth.ResolveFromEnv(forgeTarget(forgeKind))
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving %s credential: %w", forgeKind, err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
-		return false, err
+	f, err := forge.New(forgeKind, forgeCred)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	for _, note := range notes {
-		if _, found := hasJiraKey(note.Body); found {
-			return true, nil
-		}
+	jiraClient, err := jira.NewClientFromEnv(os.Getenv("JIRA_URL"), os.Getenv("JIRA_PROJECT_KEY"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("setting up Jira client: %w", err)
 	}
-	return false, nil
+
+	return f, jiraClient, nil
 }
 
-func createJiraIssue(title, description string, dryRun bool) (string, error) {
-	if dryRun {
-		fmt.Printf("[DRY-RUN] Would create Jira issue:\n  Title: %s\n  Desc: %s\n\n", title, description)
-		return "DRY-123", nil
+func run() error {
+	switch mode := getEnv("SYNC_MODE", "create"); mode {
+	case "create":
+		return runCreate()
+	case "lifecycle":
+		return runLifecycle()
+	default:
+		return fmt.Errorf("unknown SYNC_MODE %q (want create or lifecycle)", mode)
 	}
+}
 
-	jiraURL := os.Getenv("JIRA_URL")
-	jiraUser := os.Getenv("JIRA_USER")
-	jiraToken := os.Getenv("JIRA_API_TOKEN")
-	projectKey := os.Getenv("JIRA_PROJECT_KEY")
-
-	data := map[string]interface{}{
-		"fields": map[string]interface{}{
-			"project": map[string]string{
-				"key": projectKey,
-			},
-			"summary":     title,
-			"description": description,
-			"issuetype": map[string]string{
-				"name": "Task",
-			},
-		},
+// runCreate is the bot's original mode: open a Jira issue for each
+// Renovate MR that doesn't already have one linked.
+func runCreate() error {
+	dryRunEnv := os.Getenv("DRY_RUN")
+	dryRun, _ := strconv.ParseBool(dryRunEnv)
+	if dryRunEnv == "" {
+		dryRun = true
 	}
 
-	body, _ := json.Marshal(data)
-	req, _ := http.NewRequest("POST", jiraURL+"/rest/api/2/issue", bytes.NewBuffer(body))
-	req.SetBasicAuth(jiraUser, jiraToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
+	f, jiraClient, err := setup()
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer resp.Body.Close()
+	projectKey := os.Getenv("JIRA_PROJECT_KEY")
 
-	if resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("Jira error: %s", string(respBody))
+	renovateUsername := getEnv("RENOVATE_USERNAME", "renovate[bot]")
+	prs, err := f.ListOpenPullRequests(renovateUsername)
+	if err != nil {
+		return fmt.Errorf("fetching pull requests: %w", err)
 	}
 
-	var respData struct {
-		Key string `json:"key"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
-		return "", err
+	for _, pr := range prs {
+		if err := createIssueForPR(f, jiraClient, pr, projectKey, dryRun); err != nil {
+			fmt.Printf("MR %d: %v\n", pr.IID, err)
+		}
 	}
 
-	return respData.Key, nil
+	return nil
 }
 
-func commentOnMR(mrIID int, projectID, token, gitlabURL, jiraKey string, dryRun bool) error {
-	comment := fmt.Sprintf("Jira issue created: [%s](%s/browse/%s)", jiraKey, os.Getenv("JIRA_URL"), jiraKey)
-	if dryRun {
-		fmt.Printf("[DRY-RUN] Would comment on MR %d: %s\n", mrIID, comment)
+// createIssueForPR opens a Jira issue for pr unless it already has one
+// linked or matches KEYWORDS_TO_SKIP. Shared by the polling create mode
+// and the webhook server's open/reopen handler.
+func createIssueForPR(f forge.Forge, jiraClient *jira.Client, pr forge.PullRequest, projectKey string, dryRun bool) error {
+	if containsKeyword(pr) {
 		return nil
 	}
 
-	url := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes", gitlabURL, projectID, mrIID)
-	payload := map[string]string{"body": comment}
-	body, _ := json.Marshal(payload)
+	_, hasJira, err := findLinkedJiraKey(f, pr, projectKey)
+	if err != nil {
+		return fmt.Errorf("checking for linked Jira issue: %w", err)
+	}
+	if hasJira {
+		fmt.Printf("MR %d already linked to a Jira issue, skipping.\n", pr.IID)
+		return nil
+	}
 
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	req.Header.Set("PRIVATE-TOKEN", token)
-	req.Header.Set("Content-Type", "application/json")
+	ctx := jira.TemplateContext{
+		MR: jira.TemplateMR{
+			IID:         pr.IID,
+			Title:       pr.Title,
+			Description: pr.Description,
+			WebURL:      pr.WebURL,
+		},
+		Repo: getEnv("CI_PROJECT_PATH", ""),
+		Now:  time.Now(),
+	}
+	issueType := jira.IssueTypeForUpdateType("")
+	var labels []string
+	if update, ok := renovate.Parse(pr.Description); ok {
+		ctx.Packages = update.Packages()
+		ctx.UpdateType = update.DominantType()
+		issueType = jira.IssueTypeForUpdateType(update.DominantType())
+		labels = renovate.Labels(update)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	summary, err := jira.RenderSummary(ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("rendering Jira summary: %w", err)
+	}
+	descriptionText, err := jira.RenderDescription(ctx)
+	if err != nil {
+		return fmt.Errorf("rendering Jira description: %w", err)
+	}
+	description := jira.IssueDescription{
+		Text:     descriptionText,
+		MRURL:    pr.WebURL,
+		Packages: ctx.Packages,
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("GitLab comment error: %s", string(respBody))
+	jiraKey, err := jiraClient.CreateIssue(summary, description, issueType, labels, dryRun)
+	if err != nil {
+		return fmt.Errorf("creating Jira issue: %w", err)
 	}
 
+	comment := fmt.Sprintf("Jira issue created: [%s](%s/browse/%s)", jiraKey, os.Getenv("JIRA_URL"), jiraKey)
+	if dryRun {
+		fmt.Printf("[DRY-RUN] Would comment on MR %d: %s\n", pr.IID, comment)
+		return nil
+	}
+	if err := f.AddComment(pr.IID, comment); err != nil {
+		return fmt.Errorf("commenting on MR: %w", err)
+	}
 	return nil
 }
 
-func main() {
+// runLifecycle mirrors MR/PR state back onto the Jira issues already
+// linked to them: merging transitions the issue per
+// JIRA_TRANSITION_ON_MERGE, closing transitions it per
+// JIRA_TRANSITION_ON_CLOSE, and either way a label and a comment naming
+// the MR's author are added; the user who merged or closed it is not
+// recorded.
+func runLifecycle() error {
 	dryRunEnv := os.Getenv("DRY_RUN")
 	dryRun, _ := strconv.ParseBool(dryRunEnv)
 	if dryRunEnv == "" {
 		dryRun = true
 	}
-	projectID := getEnv("GITLAB_PROJECT_ID", os.Getenv("CI_PROJECT_ID"))
-	gitlabURL := getEnv("GITLAB_URL", os.Getenv("CI_SERVER_URL"))
-	token := os.Getenv("GITLAB_TOKEN")
-	mrs, err := getRenovateMRs()
+
+	f, jiraClient, err := setup()
 	if err != nil {
-		fmt.Println("Error fetching MRs:", err)
-		os.Exit(1)
+		return err
 	}
+	projectKey := os.Getenv("JIRA_PROJECT_KEY")
 
-	for _, mr := range mrs {
+	renovateUsername := getEnv("RENOVATE_USERNAME", "renovate[bot]")
+	prs, err := f.ListPullRequestsByAuthor(renovateUsername)
+	if err != nil {
+		return fmt.Errorf("fetching pull requests: %w", err)
+	}
 
-		if containsKeyword(mr) {
-			continue
-		}
-		hasJira, err := mrHasLinkedJira(mr, projectID, token, gitlabURL)
-		if err != nil {
-			fmt.Printf("Error checking MR %d: %v\n", mr.IID, err)
-			continue
-		}
-		if hasJira {
-			fmt.Printf("MR %d already linked to a Jira issue, skipping.\n", mr.IID)
-			continue
+	for _, pr := range prs {
+		if err := syncLifecycleForPR(f, jiraClient, pr, projectKey, dryRun); err != nil {
+			fmt.Printf("MR %d: %v\n", pr.IID, err)
 		}
+	}
 
-		jiraKey, err := createJiraIssue(mr.Title, mr.WebURL, dryRun)
-		if err != nil {
-			fmt.Printf("Failed to create Jira issue for MR %d: %v\n", mr.IID, err)
-			continue
-		}
+	return nil
+}
 
-		err = commentOnMR(mr.IID, projectID, token, gitlabURL, jiraKey, dryRun)
-		if err != nil {
-			fmt.Printf("Failed to comment on MR %d: %v\n", mr.IID, err)
+// syncLifecycleForPR transitions and comments on the Jira issue linked
+// to pr, if any, based on whether pr was merged or closed. Shared by
+// the polling lifecycle mode and the webhook server's merge/close
+// handler.
+func syncLifecycleForPR(f forge.Forge, jiraClient *jira.Client, pr forge.PullRequest, projectKey string, dryRun bool) error {
+	jiraKey, found, err := findLinkedJiraKey(f, pr, projectKey)
+	if err != nil {
+		return fmt.Errorf("checking for linked Jira issue: %w", err)
+	}
+	if !found {
+		return nil
+	}
+
+	var transition, comment, label string
+	switch pr.State {
+	case "merged":
+		transition = os.Getenv("JIRA_TRANSITION_ON_MERGE")
+		comment = fmt.Sprintf("gitlab-mr-merged by @%s", pr.AuthorUsername)
+		label = "gitlab-mr-merged"
+	case "closed":
+		transition = os.Getenv("JIRA_TRANSITION_ON_CLOSE")
+		comment = fmt.Sprintf("gitlab-mr-closed by @%s", pr.AuthorUsername)
+		label = "gitlab-mr-closed"
+	default:
+		return nil
+	}
+
+	if transition != "" {
+		if err := jiraClient.TransitionTo(jiraKey, transition, dryRun); err != nil {
+			fmt.Printf("Failed to transition %s for MR %d: %v\n", jiraKey, pr.IID, err)
 		}
 	}
+	if err := jiraClient.AddLabels(jiraKey, []string{label}, dryRun); err != nil {
+		fmt.Printf("Failed to label %s for MR %d: %v\n", jiraKey, pr.IID, err)
+	}
+	if err := jiraClient.AddComment(jiraKey, comment, dryRun); err != nil {
+		fmt.Printf("Failed to comment on %s for MR %d: %v\n", jiraKey, pr.IID, err)
+	}
+	return nil
+}
+
+// forgeTarget maps a forge.Kind to the auth.Target used to look up its
+// credential.
+func forgeTarget(kind forge.Kind) auth.Target {
+	switch kind {
+	case forge.KindGitHub:
+		return auth.TargetGitHub
+	case forge.KindGitea:
+		return auth.TargetGitea
+	default:
+		return auth.TargetGitLab
+	}
+}
+
+func main() {
+	var err error
+	switch {
+	case len(os.Args) >= 3 && os.Args[1] == "oauth1" && os.Args[2] == "setup":
+		err = jira.RunOAuth1Setup(os.Getenv("JIRA_URL"), os.Getenv("JIRA_OAUTH_CONSUMER_KEY"), os.Getenv("JIRA_OAUTH_PRIVATE_KEY_PEM"), os.Stdin, os.Stdout)
+	case len(os.Args) >= 2 && os.Args[1] == "serve":
+		err = runServe()
+	default:
+		err = run()
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
 }
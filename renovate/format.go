@@ -0,0 +1,20 @@
+package renovate
+
+// Labels returns the Jira labels an update should be tagged with, e.g.
+// ["renovate", "dep-update", "major", "npm"].
+func Labels(u Update) []string {
+	labels := []string{"renovate", "dep-update"}
+	if t := u.DominantType(); t != "" {
+		labels = append(labels, t)
+	}
+
+	seen := map[string]bool{}
+	for _, pkg := range u.Packages() {
+		if pkg.Manager == "" || seen[pkg.Manager] {
+			continue
+		}
+		seen[pkg.Manager] = true
+		labels = append(labels, pkg.Manager)
+	}
+	return labels
+}
@@ -0,0 +1,112 @@
+// Package renovate parses the Markdown a Renovate bot leaves in a merge
+// or pull request description into structured data about the
+// dependency update(s) it contains.
+package renovate
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Package is a single dependency bump Renovate is proposing.
+type Package struct {
+	Name           string
+	CurrentVersion string
+	NewVersion     string
+	UpdateType     string // major, minor, or patch
+	Manager        string // npm, gomod, pip, etc.
+}
+
+// Update is everything Renovate put in one MR description. Most MRs bump
+// a single package; IsGroupedUpdate holds the rest when Renovate grouped
+// several packages into one MR.
+type Update struct {
+	Package
+	IsGroupedUpdate []Package
+}
+
+var tableRowRe = regexp.MustCompile(`^\|\s*([^|]+?)\s*\|\s*([^|]+?)\s*\|\s*([^|]+?)\s*\|\s*([^|]+?)\s*\|`)
+var changeRe = regexp.MustCompile(`([0-9][^\s]*)\s*->\s*([0-9][^\s]*)`)
+var metadataRe = regexp.MustCompile(`(?i)\*\*([a-z ]+)\*\*:\s*(.+)`)
+
+// Parse extracts the packages Renovate is updating from an MR
+// description. It understands the `| Package | Type | Update | Change |`
+// table Renovate emits and the `**Key**: value` metadata blocks
+// alongside it (e.g. `**Manager**: npm`).
+func Parse(description string) (Update, bool) {
+	var packages []Package
+	manager := ""
+
+	for _, line := range strings.Split(description, "\n") {
+		if m := metadataRe.FindStringSubmatch(line); m != nil {
+			key := strings.ToLower(strings.TrimSpace(m[1]))
+			value := strings.TrimSpace(m[2])
+			switch key {
+			case "manager":
+				manager = value
+			}
+			continue
+		}
+
+		m := tableRowRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name, updateType, change := strings.TrimSpace(m[1]), strings.TrimSpace(m[3]), m[4]
+		if isHeaderOrSeparator(name) {
+			continue
+		}
+
+		pkg := Package{
+			Name:       name,
+			UpdateType: strings.ToLower(updateType),
+		}
+		if cm := changeRe.FindStringSubmatch(change); cm != nil {
+			pkg.CurrentVersion, pkg.NewVersion = cm[1], cm[2]
+		}
+		packages = append(packages, pkg)
+	}
+
+	if len(packages) == 0 {
+		return Update{}, false
+	}
+
+	for i := range packages {
+		if packages[i].Manager == "" {
+			packages[i].Manager = manager
+		}
+	}
+
+	return Update{
+		Package:         packages[0],
+		IsGroupedUpdate: packages[1:],
+	}, true
+}
+
+func isHeaderOrSeparator(name string) bool {
+	if strings.EqualFold(name, "Package") {
+		return true
+	}
+	return strings.Trim(name, "- ") == ""
+}
+
+// Packages returns every package in the update, including the grouped
+// ones, as a single slice.
+func (u Update) Packages() []Package {
+	return append([]Package{u.Package}, u.IsGroupedUpdate...)
+}
+
+// DominantType returns the most severe UpdateType across all packages in
+// the update (major > minor > patch), which is what should drive issue
+// routing for a grouped update.
+func (u Update) DominantType() string {
+	rank := map[string]int{"major": 3, "minor": 2, "patch": 1}
+	best := ""
+	bestRank := 0
+	for _, pkg := range u.Packages() {
+		if r := rank[pkg.UpdateType]; r > bestRank {
+			best, bestRank = pkg.UpdateType, r
+		}
+	}
+	return best
+}
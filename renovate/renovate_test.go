@@ -0,0 +1,125 @@
+package renovate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		wantOK      bool
+		want        Update
+	}{
+		{
+			name: "single package",
+			description: `This MR contains the following updates:
+
+| Package | Type | Update | Change |
+|---|---|---|---|
+| lodash | dependencies | minor | 4.17.20 -> 4.17.21 |
+
+**Manager**: npm
+`,
+			wantOK: true,
+			want: Update{
+				Package: Package{
+					Name:           "lodash",
+					CurrentVersion: "4.17.20",
+					NewVersion:     "4.17.21",
+					UpdateType:     "minor",
+					Manager:        "npm",
+				},
+				IsGroupedUpdate: []Package{},
+			},
+		},
+		{
+			name: "grouped update",
+			description: `This MR contains the following updates:
+
+| Package | Type | Update | Change |
+|---|---|---|---|
+| lodash | dependencies | minor | 4.17.20 -> 4.17.21 |
+| chalk | dependencies | major | 4.1.0 -> 5.0.0 |
+
+**Manager**: npm
+`,
+			wantOK: true,
+			want: Update{
+				Package: Package{
+					Name:           "lodash",
+					CurrentVersion: "4.17.20",
+					NewVersion:     "4.17.21",
+					UpdateType:     "minor",
+					Manager:        "npm",
+				},
+				IsGroupedUpdate: []Package{
+					{
+						Name:           "chalk",
+						CurrentVersion: "4.1.0",
+						NewVersion:     "5.0.0",
+						UpdateType:     "major",
+						Manager:        "npm",
+					},
+				},
+			},
+		},
+		{
+			name:        "no table",
+			description: "Just a plain description with no Renovate table.",
+			wantOK:      false,
+			want:        Update{},
+		},
+		{
+			name: "table with only header and separator",
+			description: `| Package | Type | Update | Change |
+|---|---|---|---|
+`,
+			wantOK: false,
+			want:   Update{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.description)
+			if ok != tt.wantOK {
+				t.Fatalf("Parse() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDominantType(t *testing.T) {
+	tests := []struct {
+		name string
+		u    Update
+		want string
+	}{
+		{
+			name: "single minor",
+			u:    Update{Package: Package{UpdateType: "minor"}},
+			want: "minor",
+		},
+		{
+			name: "major beats minor in a grouped update",
+			u: Update{
+				Package:         Package{UpdateType: "minor"},
+				IsGroupedUpdate: []Package{{UpdateType: "major"}, {UpdateType: "patch"}},
+			},
+			want: "major",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.u.DominantType(); got != tt.want {
+				t.Errorf("DominantType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
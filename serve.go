@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/n3amil/renovate-jira-bot/forge"
+	"github.com/n3amil/renovate-jira-bot/jira"
+)
+
+// gitlabWebhookPayload is the subset of a GitLab "Merge Request Hook"
+// payload the bot needs.
+type gitlabWebhookPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	ObjectAttributes struct {
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		State       string `json:"state"`  // opened, merged, closed
+		Action      string `json:"action"` // open, reopen, update, merge, close
+	} `json:"object_attributes"`
+}
+
+// runServe starts an HTTP server exposing /gitlab/webhook as an
+// alternative to the polling CI pipeline: it runs the same
+// create-and-lifecycle logic in near-real-time off GitLab's merge
+// request events instead of on a schedule.
+func runServe() error {
+	dryRunEnv := os.Getenv("DRY_RUN")
+	dryRun, _ := strconv.ParseBool(dryRunEnv)
+	if dryRunEnv == "" {
+		dryRun = true
+	}
+
+	f, jiraClient, err := setup()
+	if err != nil {
+		return err
+	}
+	projectKey := os.Getenv("JIRA_PROJECT_KEY")
+	renovateUsername := getEnv("RENOVATE_USERNAME", "renovate[bot]")
+	secret := os.Getenv("GITLAB_WEBHOOK_SECRET")
+	if secret == "" {
+		return fmt.Errorf("GITLAB_WEBHOOK_SECRET must be set to run serve")
+	}
+	addr := getEnv("SERVE_ADDR", ":8080")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gitlab/webhook", func(w http.ResponseWriter, r *http.Request) {
+		handleGitLabWebhook(w, r, f, jiraClient, projectKey, renovateUsername, secret, dryRun)
+	})
+
+	fmt.Printf("Listening for GitLab webhooks on %s\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// handleGitLabWebhook requires secret to be non-empty: an unset
+// GITLAB_WEBHOOK_SECRET must fail closed rather than accept every
+// unauthenticated request, so callers resolve it before runServe ever
+// starts listening.
+func handleGitLabWebhook(w http.ResponseWriter, r *http.Request, f forge.Forge, jiraClient *jira.Client, projectKey, renovateUsername, secret string, dryRun bool) {
+	if secret == "" || r.Header.Get("X-Gitlab-Token") != secret {
+		http.Error(w, "invalid webhook token", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var payload gitlabWebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if payload.User.Username != renovateUsername {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	pr := forge.PullRequest{
+		IID:            payload.ObjectAttributes.IID,
+		Title:          payload.ObjectAttributes.Title,
+		Description:    payload.ObjectAttributes.Description,
+		WebURL:         payload.ObjectAttributes.URL,
+		AuthorUsername: payload.User.Username,
+		State:          payload.ObjectAttributes.State,
+	}
+
+	var err error
+	switch payload.ObjectAttributes.Action {
+	case "open", "reopen":
+		err = createIssueForPR(f, jiraClient, pr, projectKey, dryRun)
+	case "merge", "close":
+		err = syncLifecycleForPR(f, jiraClient, pr, projectKey, dryRun)
+	}
+	if err != nil {
+		fmt.Printf("webhook: MR %d: %v\n", pr.IID, err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}